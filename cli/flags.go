@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/flashbots/mev-boost/server"
+)
+
+// relayList is a list of relays, populated from the -relay/-relays flags. It
+// implements flag.Value so each -relay occurrence appends to it.
+type relayList []server.RelayEntry
+
+func (l relayList) String() string {
+	entries := make([]string, len(l))
+	for i, entry := range l {
+		entries[i] = entry.String()
+	}
+	return strings.Join(entries, ",")
+}
+
+func (l *relayList) Set(value string) error {
+	entry, err := server.NewRelayEntry(value)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, entry)
+	return nil
+}
+
+// relayMonitorList is a list of relay monitor URLs, populated from the
+// -relay-monitor/-relay-monitors flags.
+type relayMonitorList []*url.URL
+
+func (l relayMonitorList) String() string {
+	entries := make([]string, len(l))
+	for i, entry := range l {
+		entries[i] = entry.String()
+	}
+	return strings.Join(entries, ",")
+}
+
+func (l *relayMonitorList) Set(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, u)
+	return nil
+}
@@ -1,15 +1,16 @@
 package cli
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"math/big"
 	"os"
+	"os/signal"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
-	"github.com/flashbots/go-boost-utils/types"
 	"github.com/flashbots/mev-boost/config"
 	"github.com/flashbots/mev-boost/server"
 	"github.com/sirupsen/logrus"
@@ -30,6 +31,10 @@ var (
 	defaultGenesisForkVersion = getEnv("GENESIS_FORK_VERSION", "")
 	defaultRelayMinBidEth     = getEnvFloat64("RELAY_MIN_BID", 0.001)
 	defaultDisableLogVersion  = os.Getenv("DISABLE_LOG_VERSION") == "1" // disables adding the version to every log entry
+	defaultShutdownTimeoutMs  = getEnvInt("SHUTDOWN_TIMEOUT_MS", 30000)
+	defaultOperatorAddr       = getEnv("OPERATOR_LISTEN_ADDR", "")
+	defaultUnhealthyAfter     = getEnv("UNHEALTHY_AFTER", "5m")
+	defaultClusterInstanceID  = getEnv("CLUSTER_INSTANCE_ID", defaultInstanceID())
 
 	// mev-boost relay request timeouts (see also https://github.com/flashbots/mev-boost/issues/287)
 	defaultTimeoutMsGetHeader         = getEnvInt("RELAY_TIMEOUT_MS_GETHEADER", 950)   // timeout for getHeader requests
@@ -46,6 +51,9 @@ var (
 	logDebug     = flag.Bool("debug", false, "shorthand for '-loglevel debug'")
 	logService   = flag.String("log-service", "", "add a 'service=...' tag to all log messages")
 	logNoVersion = flag.Bool("log-no-version", defaultDisableLogVersion, "disables adding the version to every log entry")
+	logFields    = flag.String("log-fields", "", "comma-separated list of key=value pairs added to every log entry, e.g. 'env=prod,region=eu'")
+
+	logRelayResponseBodies = flag.Bool("log-relay-response-bodies", false, "log truncated relay response payloads at trace level (debug only, do not use in production)")
 
 	listenAddr       = flag.String("addr", defaultListenAddr, "listen-address for mev-boost server")
 	relayURLs        = flag.String("relays", "", "relay urls - single entry or comma-separated list (scheme://pubkey@host)")
@@ -57,6 +65,19 @@ var (
 	relayTimeoutMsGetPayload = flag.Int("request-timeout-getpayload", defaultTimeoutMsGetPayload, "timeout for getPayload requests to the relay [ms]")
 	relayTimeoutMsRegVal     = flag.Int("request-timeout-regval", defaultTimeoutMsRegisterValidator, "timeout for registerValidator requests [ms]")
 
+	shutdownTimeoutMs = flag.Int("shutdown-timeout", defaultShutdownTimeoutMs, "time to allow in-flight requests to complete during graceful shutdown [ms]")
+
+	operatorAddr   = flag.String("operator-addr", defaultOperatorAddr, "separate listen-address for the operator-facing health endpoints (/-/ready, /-/healthy, /metrics); when unset they are served on -addr")
+	unhealthyAfter = flag.String("unhealthy-after", defaultUnhealthyAfter, "mark the service unhealthy if all relays have been unreachable for longer than this duration")
+
+	clusterKV         = flag.String("cluster-kv", "", "KV backend for leader election in a clustered deployment, e.g. redis://... or consul://..., defaults to disabled")
+	clusterInstanceID = flag.String("cluster-instance-id", defaultClusterInstanceID, "unique id for this instance's leader-election lease, defaults to hostname+pid")
+
+	relayRPS        = flag.Float64("relay-rps", 0, "per-relay token-bucket rate limit for outgoing requests [requests/sec], 0 means unlimited")
+	egressAllowlist = flag.String("egress-allowlist", "", "path to a YAML file listing the host:port values this instance is permitted to dial, defaults to disabled")
+
+	configPath = flag.String("config", "", "path to a YAML/TOML config file (relays, relay_monitors, min_bid, timeouts) that supersedes the equivalent flags, hot-reloaded on SIGHUP (send SIGUSR1 instead to restart onto a new binary)")
+
 	// helpers
 	useGenesisForkVersionMainnet = flag.Bool("mainnet", false, "use Mainnet")
 	useGenesisForkVersionSepolia = flag.Bool("sepolia", false, "use Sepolia")
@@ -105,6 +126,17 @@ func Main() {
 		log = log.WithField("service", *logService)
 	}
 
+	// Add arbitrary static fields to logs, if configured
+	if *logFields != "" {
+		for _, field := range strings.Split(*logFields, ",") {
+			key, value, found := strings.Cut(strings.TrimSpace(field), "=")
+			if !found {
+				log.Fatalf("invalid -log-fields entry %q, expected key=value", field)
+			}
+			log = log.WithField(key, value)
+		}
+	}
+
 	// Add version to logs and say hello
 	addVersionToLogs := !*logNoVersion
 	if addVersionToLogs {
@@ -140,7 +172,7 @@ func Main() {
 		}
 	}
 
-	if len(relays) == 0 {
+	if len(relays) == 0 && *configPath == "" {
 		flag.Usage()
 		log.Fatal("no relays specified")
 	}
@@ -174,11 +206,16 @@ func Main() {
 		log.Fatal("Minimum bid is too large, please ensure min-bid is denominated in Ethers")
 	}
 
-	relayMinBidWei, err := floatEthTo256Wei(*relayMinBidEth)
+	relayMinBidWei, err := server.FloatEthToWei(*relayMinBidEth)
 	if err != nil {
 		log.WithError(err).Fatal("failed converting min bid")
 	}
 
+	unhealthyAfterDuration, err := time.ParseDuration(*unhealthyAfter)
+	if err != nil {
+		log.WithError(err).Fatal("invalid -unhealthy-after duration")
+	}
+
 	opts := server.BoostServiceOpts{
 		Log:                      log,
 		ListenAddr:               *listenAddr,
@@ -186,10 +223,19 @@ func Main() {
 		RelayMonitors:            relayMonitors,
 		GenesisForkVersionHex:    genesisForkVersionHex,
 		RelayCheck:               *relayCheck,
-		RelayMinBid:              *relayMinBidWei,
+		RelayMinBid:              relayMinBidWei,
 		RequestTimeoutGetHeader:  time.Duration(*relayTimeoutMsGetHeader) * time.Millisecond,
 		RequestTimeoutGetPayload: time.Duration(*relayTimeoutMsGetPayload) * time.Millisecond,
 		RequestTimeoutRegVal:     time.Duration(*relayTimeoutMsRegVal) * time.Millisecond,
+		ShutdownTimeout:          time.Duration(*shutdownTimeoutMs) * time.Millisecond,
+		OperatorAddr:             *operatorAddr,
+		UnhealthyAfter:           unhealthyAfterDuration,
+		LogRelayResponseBodies:   *logRelayResponseBodies,
+		ClusterKV:                *clusterKV,
+		ClusterInstanceID:        *clusterInstanceID,
+		RelayRPS:                 *relayRPS,
+		EgressAllowlistPath:      *egressAllowlist,
+		ConfigPath:               *configPath,
 	}
 	service, err := server.NewBoostService(opts)
 	if err != nil {
@@ -200,8 +246,74 @@ func Main() {
 		log.Error("no relay passed the health-check!")
 	}
 
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// SIGHUP and SIGUSR1 are both "reload something" signals, but they reload
+	// different things and must stay distinct: SIGHUP re-reads the -config
+	// file in place (cheap, no new process), while SIGUSR1 always does the
+	// fork+exec socket handoff onto a fresh copy of the binary (needed to
+	// pick up a mev-boost version upgrade, not just a config change). With
+	// -config set, SIGHUP no longer triggers a restart by itself - send
+	// SIGUSR1 when you need to replace the running binary.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR1)
+	go func() {
+		for sig := range sigCh {
+			switch sig {
+			case syscall.SIGUSR1:
+				log.Info("received SIGUSR1, attempting graceful restart onto a new binary")
+				if err := service.Restart(); err != nil {
+					log.WithError(err).Error("graceful restart failed, continuing to serve")
+				}
+				continue
+			case syscall.SIGHUP:
+				if *configPath != "" {
+					log.Info("received SIGHUP, reloading config")
+					if err := service.ReloadConfig(*configPath); err != nil {
+						log.WithError(err).Error("config reload failed, keeping previous configuration")
+					}
+				} else {
+					log.Info("received SIGHUP, attempting graceful restart (no -config set; send SIGUSR1 to restart explicitly)")
+					if err := service.Restart(); err != nil {
+						log.WithError(err).Error("graceful restart failed, continuing to serve")
+					}
+				}
+				continue
+			}
+			log.Infof("received %s, starting graceful shutdown", sig)
+			cancel()
+			return
+		}
+	}()
+
+	if *operatorAddr != "" {
+		log.Println("operator endpoints listening on", *operatorAddr)
+	}
+
+	if *clusterKV != "" {
+		log.Infof("clustering enabled via %s, instance id: %s", *clusterKV, *clusterInstanceID)
+	}
+
+	if *configPath != "" {
+		log.Infof("using config file %s, reload with SIGHUP", *configPath)
+	}
+
 	log.Println("listening on", *listenAddr)
-	log.Fatal(service.StartHTTPServer())
+	if err := service.StartHTTPServer(ctx); err != nil {
+		log.WithError(err).Fatal("HTTP server terminated unexpectedly")
+	}
+	log.Info("shutdown complete")
+}
+
+// defaultInstanceID returns a best-effort unique identifier for this process, used to
+// own the leader-election lease when no -cluster-instance-id is given explicitly.
+func defaultInstanceID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
 }
 
 func getEnv(key, defaultValue string) string {
@@ -231,20 +343,3 @@ func getEnvFloat64(key string, defaultValue float64) float64 {
 	return defaultValue
 }
 
-func floatEthTo256Wei(val float64) (*types.U256Str, error) {
-	bigval := new(big.Float)
-	bigval.SetFloat64(val)
-
-	wad := new(big.Float)
-	wad.SetInt(big.NewInt(1000000000000000000))
-
-	bigval.Mul(bigval, wad)
-
-	result := new(big.Int)
-	bigval.Int(result)
-
-	u256 := new(types.U256Str)
-	err := u256.FromBig(result)
-
-	return u256, err
-}
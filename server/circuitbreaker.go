@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	circuitBreakerFailureThreshold = 5
+	circuitBreakerCooldown         = 30 * time.Second
+)
+
+// circuitBreaker opens after circuitBreakerFailureThreshold consecutive
+// failures to a single relay, short-circuiting further calls to that relay
+// for circuitBreakerCooldown instead of waiting out a full request timeout.
+type circuitBreaker struct {
+	mu              sync.Mutex
+	consecutiveFail int
+	openUntil       time.Time
+}
+
+func (b *circuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return time.Now().After(b.openUntil)
+}
+
+func (b *circuitBreaker) RecordResult(ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ok {
+		b.consecutiveFail = 0
+		b.openUntil = time.Time{}
+		return
+	}
+	b.consecutiveFail++
+	if b.consecutiveFail >= circuitBreakerFailureThreshold {
+		b.openUntil = time.Now().Add(circuitBreakerCooldown)
+	}
+}
+
+// rateLimiter is a simple token bucket limiting outgoing requests to one relay.
+type rateLimiter struct {
+	mu         sync.Mutex
+	rps        float64
+	tokens     float64
+	lastRefill time.Time
+}
+
+func newRateLimiter(rps float64) *rateLimiter {
+	return &rateLimiter{rps: rps, tokens: rps, lastRefill: time.Now()}
+}
+
+func (l *rateLimiter) Allow() bool {
+	if l.rps <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.lastRefill).Seconds() * l.rps
+	// The bucket holds at least one token so that rates below 1/sec still
+	// admit a call once enough time has passed.
+	burst := l.rps
+	if burst < 1 {
+		burst = 1
+	}
+	if l.tokens > burst {
+		l.tokens = burst
+	}
+	l.lastRefill = now
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// breakerFor and limiterFor lazily create per-relay state the first time a
+// relay is seen.
+func (m *BoostService) breakerFor(relay string) *circuitBreaker {
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
+	b, ok := m.breakers[relay]
+	if !ok {
+		b = &circuitBreaker{}
+		m.breakers[relay] = b
+	}
+	return b
+}
+
+func (m *BoostService) limiterFor(relay string) *rateLimiter {
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
+	l, ok := m.limiters[relay]
+	if !ok {
+		l = newRateLimiter(m.relayRPS)
+		m.limiters[relay] = l
+	}
+	return l
+}
+
+// allowRelayCall gates an outgoing relay call through its rate limiter and
+// circuit breaker, recording a Prometheus rejection counter and returning an
+// error naming whichever one rejected the call.
+func (m *BoostService) allowRelayCall(relay string) error {
+	if !m.breakerFor(relay).Allow() {
+		circuitBreakerRejections.WithLabelValues(relay).Inc()
+		return fmt.Errorf("circuit breaker open for relay %s", relay)
+	}
+	if !m.limiterFor(relay).Allow() {
+		rateLimitRejections.WithLabelValues(relay).Inc()
+		return fmt.Errorf("rate limit exceeded for relay %s", relay)
+	}
+	return nil
+}
+
+// recordRelayCallResult feeds the outcome of an outgoing relay call back into
+// its circuit breaker.
+func (m *BoostService) recordRelayCallResult(relay string, ok bool) {
+	m.breakerFor(relay).RecordResult(ok)
+}
+
+// egressAllowlist restricts outbound dials to an explicit set of host:port
+// values, loaded from a YAML file, to mitigate SSRF-style relay misconfiguration.
+type egressAllowlist struct {
+	hosts map[string]struct{}
+}
+
+func loadEgressAllowlist(path string) (*egressAllowlist, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var hosts []string
+	if err := yaml.Unmarshal(data, &hosts); err != nil {
+		return nil, fmt.Errorf("invalid egress allowlist yaml: %w", err)
+	}
+	set := make(map[string]struct{}, len(hosts))
+	for _, h := range hosts {
+		set[h] = struct{}{}
+	}
+	return &egressAllowlist{hosts: set}, nil
+}
+
+// Allowed reports whether host:port is permitted to be dialed.
+func (a *egressAllowlist) Allowed(hostport string) bool {
+	if a == nil {
+		return true
+	}
+	_, ok := a.hosts[hostport]
+	return ok
+}
+
+// dialContext returns a net.Dialer.DialContext-compatible func that refuses
+// to dial anything outside the configured egress allowlist, for use as an
+// http.Transport.DialContext override on the relay HTTP client.
+func (m *BoostService) dialContext(dialer *net.Dialer) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if m.allowlist != nil && !m.allowlist.Allowed(addr) {
+			return nil, fmt.Errorf("egress to %s is not in the allowlist", addr)
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+}
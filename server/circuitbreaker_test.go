@@ -0,0 +1,105 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAfterThreshold(t *testing.T) {
+	b := &circuitBreaker{}
+
+	for i := 0; i < circuitBreakerFailureThreshold-1; i++ {
+		b.RecordResult(false)
+		if !b.Allow() {
+			t.Fatalf("breaker opened after %d failures, want %d", i+1, circuitBreakerFailureThreshold)
+		}
+	}
+
+	b.RecordResult(false)
+	if b.Allow() {
+		t.Fatalf("breaker did not open after %d consecutive failures", circuitBreakerFailureThreshold)
+	}
+}
+
+func TestCircuitBreakerRecoversAfterCooldown(t *testing.T) {
+	b := &circuitBreaker{}
+	for i := 0; i < circuitBreakerFailureThreshold; i++ {
+		b.RecordResult(false)
+	}
+	if b.Allow() {
+		t.Fatal("breaker should be open immediately after tripping")
+	}
+
+	b.openUntil = time.Now().Add(-time.Second) // simulate cooldown having elapsed
+	if !b.Allow() {
+		t.Fatal("breaker should allow calls again once the cooldown has elapsed")
+	}
+}
+
+func TestCircuitBreakerResetsOnSuccess(t *testing.T) {
+	b := &circuitBreaker{}
+	b.RecordResult(false)
+	b.RecordResult(false)
+	b.RecordResult(true)
+
+	for i := 0; i < circuitBreakerFailureThreshold-1; i++ {
+		b.RecordResult(false)
+		if !b.Allow() {
+			t.Fatalf("breaker opened early after %d failures following a success reset", i+1)
+		}
+	}
+}
+
+func TestRateLimiterUnlimitedWhenZero(t *testing.T) {
+	l := newRateLimiter(0)
+	for i := 0; i < 100; i++ {
+		if !l.Allow() {
+			t.Fatal("rate limiter with rps=0 should never reject")
+		}
+	}
+}
+
+func TestRateLimiterRejectsOverBudget(t *testing.T) {
+	l := newRateLimiter(1)
+	if !l.Allow() {
+		t.Fatal("first call should consume the initial token")
+	}
+	if l.Allow() {
+		t.Fatal("second immediate call should be rejected, budget already spent")
+	}
+}
+
+func TestRateLimiterRefillsOverTime(t *testing.T) {
+	l := newRateLimiter(1)
+	l.Allow() // spend the initial token
+	l.lastRefill = time.Now().Add(-2 * time.Second)
+	if !l.Allow() {
+		t.Fatal("rate limiter should refill a token after enough elapsed time")
+	}
+}
+
+func TestAllowRelayCallRejectsOpenBreakerAndOverLimit(t *testing.T) {
+	m := &BoostService{
+		breakers: make(map[string]*circuitBreaker),
+		limiters: make(map[string]*rateLimiter),
+	}
+
+	if err := m.allowRelayCall("relay-a"); err != nil {
+		t.Fatalf("first call should be allowed, got %v", err)
+	}
+	m.recordRelayCallResult("relay-a", false)
+	for i := 1; i < circuitBreakerFailureThreshold; i++ {
+		m.recordRelayCallResult("relay-a", false)
+	}
+	if err := m.allowRelayCall("relay-a"); err == nil {
+		t.Fatal("expected the open circuit breaker to reject the call")
+	}
+
+	m.relayRPS = 1
+	if err := m.allowRelayCall("relay-b"); err != nil {
+		t.Fatalf("first call to relay-b should be allowed, got %v", err)
+	}
+	if err := m.allowRelayCall("relay-b"); err == nil {
+		t.Fatal("expected the rate limiter to reject the second immediate call")
+	}
+}
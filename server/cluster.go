@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	clusterLeaseKey      = "mev-boost/leader"
+	clusterLeaseTTL      = 15 * time.Second
+	clusterHeartbeatTick = 5 * time.Second
+)
+
+// kvBackend is the minimal compare-and-swap KV interface the leader elector
+// and the getPayload dedup lock need. Backed by Redis (SET NX/XX PX) today;
+// -cluster-kv's consul:// scheme is recognized but not yet implemented.
+type kvBackend interface {
+	// TryAcquire sets key=value only if it doesn't already hold an
+	// unexpired value, with the given TTL. Returns true if this call won the lease.
+	TryAcquire(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// Refresh extends the TTL of a lease this instance currently holds.
+	Refresh(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+	// Release gives up a lease this instance currently holds.
+	Release(ctx context.Context, key, value string) error
+	// Store unconditionally sets key=value with the given TTL.
+	Store(ctx context.Context, key, value string, ttl time.Duration) error
+	// Get returns the current value of key, if any.
+	Get(ctx context.Context, key string) (value string, ok bool, err error)
+}
+
+// clusterElector runs leader election for a batch of mev-boost instances
+// sharing a validator set, so only the leader forwards registerValidator to
+// relays; non-leaders still serve getHeader locally.
+type clusterElector struct {
+	kv         kvBackend
+	instanceID string
+	log        *logrus.Entry
+
+	mu       sync.RWMutex
+	isLeader bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newClusterElector(kvURL, instanceID string, log *logrus.Entry) (*clusterElector, error) {
+	u, err := url.Parse(kvURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -cluster-kv url: %w", err)
+	}
+
+	var kv kvBackend
+	switch u.Scheme {
+	case "redis":
+		kv, err = newRedisKV(u)
+	case "consul":
+		err = fmt.Errorf("consul cluster-kv backend is not yet implemented, use redis:// for now")
+	default:
+		err = fmt.Errorf("unsupported -cluster-kv scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	e := &clusterElector{
+		kv:         kv,
+		instanceID: instanceID,
+		log:        log.WithField("component", "cluster"),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go e.run()
+	return e, nil
+}
+
+func (e *clusterElector) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(clusterHeartbeatTick)
+	defer ticker.Stop()
+
+	e.tick()
+	for {
+		select {
+		case <-ticker.C:
+			e.tick()
+		case <-e.stop:
+			e.stepDown()
+			return
+		}
+	}
+}
+
+func (e *clusterElector) tick() {
+	ctx, cancel := context.WithTimeout(context.Background(), clusterHeartbeatTick)
+	defer cancel()
+
+	e.mu.RLock()
+	wasLeader := e.isLeader
+	e.mu.RUnlock()
+
+	var won bool
+	var err error
+	if wasLeader {
+		won, err = e.kv.Refresh(ctx, clusterLeaseKey, e.instanceID, clusterLeaseTTL)
+	} else {
+		won, err = e.kv.TryAcquire(ctx, clusterLeaseKey, e.instanceID, clusterLeaseTTL)
+	}
+	if err != nil {
+		e.log.WithError(err).Warn("leader election heartbeat failed")
+		won = false
+	}
+
+	e.mu.Lock()
+	if won != wasLeader {
+		if won {
+			e.log.Info("acquired cluster leadership")
+		} else {
+			e.log.Warn("lost cluster leadership")
+		}
+	}
+	e.isLeader = won
+	e.mu.Unlock()
+}
+
+func (e *clusterElector) stepDown() {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if !e.isLeader {
+		return
+	}
+	if err := e.kv.Release(ctx, clusterLeaseKey, e.instanceID); err != nil {
+		e.log.WithError(err).Warn("failed releasing leadership on shutdown")
+	}
+	e.isLeader = false
+}
+
+// IsLeader reports whether this instance currently holds the
+// registerValidator lease.
+func (e *clusterElector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.isLeader
+}
+
+// clusterPayloadLockTTL bounds how long a claim on a (slot, blockHash) pair
+// is held before another instance is allowed to retry it, in case the
+// claiming instance dies mid-fetch.
+const clusterPayloadLockTTL = 10 * time.Second
+
+func payloadLockKey(slot, blockHash string) string {
+	return fmt.Sprintf("mev-boost/payload-lock/%s/%s", slot, blockHash)
+}
+
+func payloadCacheKey(slot, blockHash string) string {
+	return fmt.Sprintf("mev-boost/payload/%s/%s", slot, blockHash)
+}
+
+// ClaimPayload attempts to become the instance responsible for fetching
+// getPayload for (slot, blockHash) from the relays, so that concurrent
+// instances sharing a validator set don't all hit every relay for the same
+// payload; losers should poll CachedPayload instead.
+func (e *clusterElector) ClaimPayload(ctx context.Context, slot, blockHash string) (bool, error) {
+	return e.kv.TryAcquire(ctx, payloadLockKey(slot, blockHash), e.instanceID, clusterPayloadLockTTL)
+}
+
+// CachePayload stores the payload retrieved from the relays so other
+// instances waiting on CachedPayload for the same (slot, blockHash) can reuse
+// it instead of calling the relays themselves.
+func (e *clusterElector) CachePayload(ctx context.Context, slot, blockHash, payloadJSON string) error {
+	return e.kv.Store(ctx, payloadCacheKey(slot, blockHash), payloadJSON, clusterPayloadLockTTL)
+}
+
+// CachedPayload returns the payload another instance already retrieved for
+// (slot, blockHash), if any.
+func (e *clusterElector) CachedPayload(ctx context.Context, slot, blockHash string) (string, bool) {
+	value, ok, err := e.kv.Get(ctx, payloadCacheKey(slot, blockHash))
+	if err != nil || !ok {
+		return "", false
+	}
+	return value, true
+}
+
+// Stop steps down as leader (if applicable) and stops the heartbeat loop.
+func (e *clusterElector) Stop() {
+	close(e.stop)
+	<-e.done
+}
@@ -0,0 +1,150 @@
+package server
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// relayOverride holds the per-relay knobs a config file may set. Enabled is a
+// *bool rather than a bool so that an override entry which only sets Weight
+// or MaxBidCap (and never mentions "enabled") doesn't get its zero value
+// misread as an explicit "disabled": nil means "not specified", leave the
+// relay's default (enabled) in place.
+type relayOverride struct {
+	Weight    int    `yaml:"weight" toml:"weight"`
+	Enabled   *bool  `yaml:"enabled" toml:"enabled"`
+	MaxBidCap string `yaml:"max_bid_cap" toml:"max_bid_cap"`
+}
+
+func (o relayOverride) disabled() bool {
+	return o.Enabled != nil && !*o.Enabled
+}
+
+// fileConfig mirrors the structure of the -config YAML/TOML file.
+type fileConfig struct {
+	Relays                   []string                 `yaml:"relays" toml:"relays"`
+	RelayMonitors            []string                 `yaml:"relay_monitors" toml:"relay_monitors"`
+	MinBid                   float64                  `yaml:"min_bid" toml:"min_bid"`
+	RequestTimeoutGetHeader  int                      `yaml:"request_timeout_getheader" toml:"request_timeout_getheader"`
+	RequestTimeoutGetPayload int                      `yaml:"request_timeout_getpayload" toml:"request_timeout_getpayload"`
+	RequestTimeoutRegVal     int                      `yaml:"request_timeout_regval" toml:"request_timeout_regval"`
+	RelayOverrides           map[string]relayOverride `yaml:"relay_overrides" toml:"relay_overrides"`
+}
+
+// unmarshalConfig parses data as TOML when path ends in .toml, and as YAML
+// otherwise, matching the "-config" flag's advertised "YAML/TOML" support.
+func unmarshalConfig(path string, data []byte, cfg *fileConfig) error {
+	if strings.EqualFold(filepath.Ext(path), ".toml") {
+		return toml.Unmarshal(data, cfg)
+	}
+	return yaml.Unmarshal(data, cfg)
+}
+
+// ReloadConfig re-reads the -config file and atomically swaps the relay set
+// in use (via m.relays, an atomic.Pointer), so in-flight requests keep using
+// their own snapshot. On a parse failure the previous configuration is kept
+// and the failure is logged and counted via mev_boost_config_reloads_total.
+func (m *BoostService) ReloadConfig(path string) (err error) {
+	defer func() {
+		result := "success"
+		if err != nil {
+			result = "failure"
+		}
+		configReloadsTotal.WithLabelValues(result).Inc()
+	}()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed reading config file: %w", err)
+	}
+
+	var cfg fileConfig
+	if err = unmarshalConfig(path, data, &cfg); err != nil {
+		return fmt.Errorf("failed parsing config file: %w", err)
+	}
+
+	relays := make([]RelayEntry, 0, len(cfg.Relays))
+	for _, relayURL := range cfg.Relays {
+		var entry RelayEntry
+		entry, err = NewRelayEntry(relayURL)
+		if err != nil {
+			return fmt.Errorf("invalid relay %q in config file: %w", relayURL, err)
+		}
+		if cfg.RelayOverrides[entry.URL.Host].disabled() {
+			continue
+		}
+		relays = append(relays, entry)
+	}
+	if len(relays) == 0 {
+		err = fmt.Errorf("config file %s defines no enabled relays", path)
+		return err
+	}
+
+	relayMonitors := m.relayMonitors
+	if len(cfg.RelayMonitors) > 0 {
+		relayMonitors = make([]*url.URL, 0, len(cfg.RelayMonitors))
+		for _, monitorURL := range cfg.RelayMonitors {
+			var u *url.URL
+			u, err = url.Parse(monitorURL)
+			if err != nil {
+				return fmt.Errorf("invalid relay monitor %q in config file: %w", monitorURL, err)
+			}
+			relayMonitors = append(relayMonitors, u)
+		}
+	}
+
+	minBid := m.relayMinBid
+	if cfg.MinBid > 0 {
+		minBid, err = FloatEthToWei(cfg.MinBid)
+		if err != nil {
+			return fmt.Errorf("invalid min_bid in config file: %w", err)
+		}
+	}
+
+	m.relays.Store(&relays)
+	m.relayMonitors = relayMonitors
+	m.relayMinBid = minBid
+	m.relayOverrides = cfg.RelayOverrides
+
+	if cfg.RequestTimeoutGetHeader > 0 {
+		m.requestTimeoutGetHeader = time.Duration(cfg.RequestTimeoutGetHeader) * time.Millisecond
+	}
+	if cfg.RequestTimeoutGetPayload > 0 {
+		m.requestTimeoutGetPayload = time.Duration(cfg.RequestTimeoutGetPayload) * time.Millisecond
+	}
+	if cfg.RequestTimeoutRegVal > 0 {
+		m.requestTimeoutRegVal = time.Duration(cfg.RequestTimeoutRegVal) * time.Millisecond
+	}
+
+	m.configPath = path
+	m.log.Infof("config reloaded from %s: %d relays active", path, len(relays))
+	return nil
+}
+
+// relayBidCapWei returns relay's configured max_bid_cap in wei, or nil if
+// none is set, so getHeader can clamp an over-reporting relay's bid.
+func (m *BoostService) relayBidCapWei(relay RelayEntry) *big.Int {
+	override, ok := m.relayOverrides[relay.URL.Host]
+	if !ok || override.MaxBidCap == "" {
+		return nil
+	}
+	cap, ok := new(big.Int).SetString(override.MaxBidCap, 10)
+	if !ok {
+		return nil
+	}
+	return cap
+}
+
+// relayWeight returns relay's configured tie-break weight (0 if unset), used
+// by getHeader to pick among equal-value bids.
+func (m *BoostService) relayWeight(relay RelayEntry) int {
+	return m.relayOverrides[relay.URL.Host].Weight
+}
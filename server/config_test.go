@@ -0,0 +1,147 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestRelayOverrideDisabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		override relayOverride
+		want     bool
+	}{
+		{"unspecified leaves relay enabled", relayOverride{Weight: 5}, false},
+		{"explicit enabled=true stays enabled", relayOverride{Enabled: boolPtr(true)}, false},
+		{"explicit enabled=false disables", relayOverride{Enabled: boolPtr(false)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.override.disabled(); got != tt.want {
+				t.Errorf("disabled() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReloadConfigYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "relays.yaml")
+	writeFile(t, path, `
+relays:
+  - https://0xaaaa@relay-a.example.com
+  - https://0xbbbb@relay-b.example.com
+relay_monitors:
+  - https://monitor.example.com
+min_bid: 0.05
+relay_overrides:
+  relay-b.example.com:
+    weight: 7
+    max_bid_cap: "123456"
+`)
+
+	m := &BoostService{log: logrus.NewEntry(logrus.New())}
+	if err := m.ReloadConfig(path); err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+
+	relays := *m.relays.Load()
+	if len(relays) != 2 {
+		t.Fatalf("expected 2 relays, got %d", len(relays))
+	}
+	if len(m.relayMonitors) != 1 || m.relayMonitors[0].String() != "https://monitor.example.com" {
+		t.Fatalf("relay_monitors not applied, got %v", m.relayMonitors)
+	}
+
+	wantMinBid, err := FloatEthToWei(0.05)
+	if err != nil {
+		t.Fatalf("FloatEthToWei failed: %v", err)
+	}
+	if m.relayMinBid.String() != wantMinBid.String() {
+		t.Fatalf("min_bid not applied: got %s, want %s", m.relayMinBid.String(), wantMinBid.String())
+	}
+
+	if weight := m.relayWeight(relays[1]); weight != 7 {
+		t.Fatalf("relay_overrides weight not applied: got %d, want 7", weight)
+	}
+	if cap := m.relayBidCapWei(relays[1]); cap == nil || cap.String() != "123456" {
+		t.Fatalf("relay_overrides max_bid_cap not applied: got %v", cap)
+	}
+}
+
+func TestReloadConfigDisabledOverrideDropsRelay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "relays.yaml")
+	writeFile(t, path, `
+relays:
+  - https://0xaaaa@relay-a.example.com
+  - https://0xbbbb@relay-b.example.com
+relay_overrides:
+  relay-b.example.com:
+    enabled: false
+`)
+
+	m := &BoostService{log: logrus.NewEntry(logrus.New())}
+	if err := m.ReloadConfig(path); err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+
+	relays := *m.relays.Load()
+	if len(relays) != 1 || relays[0].URL.Host != "relay-a.example.com" {
+		t.Fatalf("expected only relay-a to remain enabled, got %v", relays)
+	}
+}
+
+func TestReloadConfigUnspecifiedEnabledKeepsRelay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "relays.yaml")
+	writeFile(t, path, `
+relays:
+  - https://0xaaaa@relay-a.example.com
+relay_overrides:
+  relay-a.example.com:
+    weight: 3
+`)
+
+	m := &BoostService{log: logrus.NewEntry(logrus.New())}
+	if err := m.ReloadConfig(path); err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+
+	relays := *m.relays.Load()
+	if len(relays) != 1 {
+		t.Fatalf("override without 'enabled' should not disable the relay, got %v", relays)
+	}
+}
+
+func TestReloadConfigTOML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "relays.toml")
+	writeFile(t, path, `
+relays = ["https://0xaaaa@relay-a.example.com"]
+
+[relay_overrides."relay-a.example.com"]
+weight = 4
+`)
+
+	m := &BoostService{log: logrus.NewEntry(logrus.New())}
+	if err := m.ReloadConfig(path); err != nil {
+		t.Fatalf("ReloadConfig failed: %v", err)
+	}
+
+	relays := *m.relays.Load()
+	if len(relays) != 1 {
+		t.Fatalf("expected 1 relay from toml config, got %d", len(relays))
+	}
+	if weight := m.relayWeight(relays[0]); weight != 4 {
+		t.Fatalf("toml relay_overrides weight not applied: got %d, want 4", weight)
+	}
+}
+
+func writeFile(t *testing.T, path, contents string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("failed writing test config file: %v", err)
+	}
+}
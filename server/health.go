@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// healthPollInterval is how often pollRelayHealth re-checks every relay's
+// status endpoint in the background, independent of -relay-check (which only
+// gates the one-off startup check). Without this, lastRelayOK/lastRelayErr
+// would never update past process start, so -unhealthy-after could never
+// trigger once a relay went down mid-run.
+const healthPollInterval = 30 * time.Second
+
+// registerHealthRoutes wires up the operator-facing liveness/readiness probes
+// and the Prometheus scrape endpoint.
+func (m *BoostService) registerHealthRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/-/ready", m.handleReady)
+	mux.HandleFunc("/-/healthy", m.handleHealthy)
+	mux.Handle("/metrics", promhttp.Handler())
+}
+
+// pollRelayHealth periodically re-checks every relay's status endpoint so
+// /-/healthy's unreachable-for-longer-than -unhealthy-after logic reflects a
+// relay going down at any point during the run, not just at startup. Runs
+// until ctx is canceled.
+func (m *BoostService) pollRelayHealth(ctx context.Context) {
+	ticker := time.NewTicker(healthPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.CheckRelays()
+		}
+	}
+}
+
+// handleReady returns 200 once the HTTP server has bound and, if -relay-check
+// is set, the initial relay startup check has completed.
+func (m *BoostService) handleReady(w http.ResponseWriter, _ *http.Request) {
+	if !m.listening.Load() || !m.relayCheckDone.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+type healthyResponse struct {
+	Healthy      bool     `json:"healthy"`
+	LastError    string   `json:"last_error,omitempty"`
+	ShuttingDown bool     `json:"shutting_down"`
+	OpenBreakers []string `json:"open_breakers,omitempty"`
+}
+
+// openBreakers lists the relays whose circuit breaker is currently open.
+func (m *BoostService) openBreakers() []string {
+	m.breakersMu.Lock()
+	defer m.breakersMu.Unlock()
+	var open []string
+	for relay, b := range m.breakers {
+		if !b.Allow() {
+			open = append(open, relay)
+		}
+	}
+	return open
+}
+
+// handleHealthy returns 503 while a graceful shutdown is in progress, or if
+// all relays have been unreachable for longer than -unhealthy-after.
+func (m *BoostService) handleHealthy(w http.ResponseWriter, _ *http.Request) {
+	unhealthy := m.shuttingDown.Load()
+	if !unhealthy && m.unhealthyAfter > 0 {
+		lastOK, _ := m.lastRelayOK.Load().(time.Time)
+		unhealthy = time.Since(lastOK) > m.unhealthyAfter
+	}
+
+	resp := healthyResponse{
+		Healthy:      !unhealthy,
+		LastError:    m.lastRelayErr.Load().(string),
+		ShuttingDown: m.shuttingDown.Load(),
+		OpenBreakers: m.openBreakers(),
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if unhealthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
+	_ = json.NewEncoder(w).Encode(resp)
+}
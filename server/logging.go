@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+type ctxKey int
+
+const ctxKeyLog ctxKey = iota
+
+// newRequestID generates a short random id to correlate a beacon request with
+// the relay calls it fans out to, without pulling in a UUID dependency for
+// what is just an opaque correlation token.
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return fmt.Sprintf("%x", b)
+}
+
+// withRequestLogger wraps a beacon-facing handler so every request gets a
+// unique X-Request-ID (to propagate to relays) and a logger carrying it and
+// the method name, reachable downstream via loggerFromContext. Slot, parent
+// hash and proposer pubkey are not added to the logger.
+func (m *BoostService) withRequestLogger(method string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestID := newRequestID()
+		w.Header().Set("X-Request-ID", requestID)
+
+		entry := m.log.WithFields(logrus.Fields{
+			"requestID": requestID,
+			"method":    method,
+		})
+
+		ctx := context.WithValue(r.Context(), ctxKeyLog, entry)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+// loggerFromContext returns the per-request logger injected by
+// withRequestLogger, falling back to the service-level logger.
+func (m *BoostService) loggerFromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(ctxKeyLog).(*logrus.Entry); ok {
+		return entry
+	}
+	return m.log
+}
+
+// relayRequestID extracts the request id set by withRequestLogger, for
+// attaching to outgoing relay calls as X-Request-ID.
+func relayRequestID(ctx context.Context) string {
+	entry, ok := ctx.Value(ctxKeyLog).(*logrus.Entry)
+	if !ok {
+		return ""
+	}
+	requestID, _ := entry.Data["requestID"].(string)
+	return requestID
+}
+
+// logRelayResponseBody logs a truncated relay response body at trace level,
+// gated by -log-relay-response-bodies since bodies can be large or contain
+// bid details operators may not want at a lower log level.
+func (m *BoostService) logRelayResponseBody(ctx context.Context, relay string, body []byte) {
+	if !m.logRelayResponseBodies {
+		return
+	}
+	const maxLen = 2048
+	truncated := body
+	if len(truncated) > maxLen {
+		truncated = truncated[:maxLen]
+	}
+	m.loggerFromContext(ctx).WithField("relay", relay).Tracef("relay response body: %s", truncated)
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	circuitBreakerRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mev_boost_circuit_breaker_rejections_total",
+		Help: "Number of relay calls short-circuited by an open circuit breaker.",
+	}, []string{"relay"})
+
+	rateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mev_boost_rate_limit_rejections_total",
+		Help: "Number of relay calls rejected by the per-relay rate limiter.",
+	}, []string{"relay"})
+
+	configReloadsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mev_boost_config_reloads_total",
+		Help: "Number of -config file reload attempts, labeled by result.",
+	}, []string{"result"})
+)
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// redisKV is a minimal Redis client implementing just the SET NX/XX PX / GET
+// / DEL primitives the cluster elector needs, via the RESP wire protocol
+// directly, so leader election doesn't need a full client dependency for a
+// handful of commands.
+type redisKV struct {
+	addr string
+}
+
+func newRedisKV(u *url.URL) (*redisKV, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("redis -cluster-kv url must include a host, got %q", u.String())
+	}
+	return &redisKV{addr: u.Host}, nil
+}
+
+func (r *redisKV) do(ctx context.Context, args ...string) (string, bool, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return "", false, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&sb, "$%d\r\n%s\r\n", len(a), a)
+	}
+	if _, err := conn.Write([]byte(sb.String())); err != nil {
+		return "", false, err
+	}
+
+	reader := bufio.NewReader(conn)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", false, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if len(line) == 0 {
+		return "", false, fmt.Errorf("empty redis reply")
+	}
+
+	switch line[0] {
+	case '-':
+		return "", false, fmt.Errorf("redis error: %s", line[1:])
+	case '+':
+		return line[1:], true, nil
+	case '$':
+		n := 0
+		fmt.Sscanf(line[1:], "%d", &n)
+		if n < 0 {
+			return "", false, nil // nil bulk string
+		}
+		buf := make([]byte, n+2)
+		if _, err := readFull(reader, buf); err != nil {
+			return "", false, err
+		}
+		return string(buf[:n]), true, nil
+	default:
+		return line, true, nil
+	}
+}
+
+func readFull(r *bufio.Reader, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := r.Read(buf[total:])
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+func (r *redisKV) TryAcquire(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	reply, ok, err := r.do(ctx, "SET", key, value, "NX", "PX", fmt.Sprintf("%d", ttl.Milliseconds()))
+	if err != nil {
+		return false, err
+	}
+	return ok && reply == "OK", nil
+}
+
+func (r *redisKV) Refresh(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	current, ok, err := r.do(ctx, "GET", key)
+	if err != nil {
+		return false, err
+	}
+	if !ok || current != value {
+		return r.TryAcquire(ctx, key, value, ttl)
+	}
+	reply, ok, err := r.do(ctx, "SET", key, value, "XX", "PX", fmt.Sprintf("%d", ttl.Milliseconds()))
+	if err != nil {
+		return false, err
+	}
+	return ok && reply == "OK", nil
+}
+
+func (r *redisKV) Store(ctx context.Context, key, value string, ttl time.Duration) error {
+	_, _, err := r.do(ctx, "SET", key, value, "PX", fmt.Sprintf("%d", ttl.Milliseconds()))
+	return err
+}
+
+func (r *redisKV) Get(ctx context.Context, key string) (string, bool, error) {
+	return r.do(ctx, "GET", key)
+}
+
+func (r *redisKV) Release(ctx context.Context, key, value string) error {
+	current, ok, err := r.do(ctx, "GET", key)
+	if err != nil {
+		return err
+	}
+	if !ok || current != value {
+		return nil
+	}
+	_, _, err = r.do(ctx, "DEL", key)
+	return err
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRedisServer accepts a single connection and replies to each RESP
+// request with the next scripted reply, so redisKV's wire-level parsing can
+// be tested without a real Redis instance.
+func fakeRedisServer(t *testing.T, replies ...string) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed starting fake redis listener: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for _, reply := range replies {
+			// Drain one RESP array request (*N\r\n then N bulk strings) before replying.
+			line, err := reader.ReadString('\n')
+			if err != nil || !strings.HasPrefix(line, "*") {
+				return
+			}
+			n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+			if err != nil {
+				return
+			}
+			for i := 0; i < n; i++ {
+				if _, err := reader.ReadString('\n'); err != nil { // $len
+					return
+				}
+				if _, err := reader.ReadString('\n'); err != nil { // bulk value
+					return
+				}
+			}
+			if _, err := conn.Write([]byte(reply)); err != nil {
+				return
+			}
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func bulkReply(value string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(value), value)
+}
+
+func TestRedisKVTryAcquireSuccess(t *testing.T) {
+	addr := fakeRedisServer(t, "+OK\r\n")
+	kv := &redisKV{addr: addr}
+
+	ok, err := kv.TryAcquire(context.Background(), "mev-boost/leader", "instance-a", 15*time.Second)
+	if err != nil {
+		t.Fatalf("TryAcquire returned error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected TryAcquire to succeed on a +OK reply")
+	}
+}
+
+func TestRedisKVTryAcquireFailsOnNilReply(t *testing.T) {
+	addr := fakeRedisServer(t, "$-1\r\n")
+	kv := &redisKV{addr: addr}
+
+	ok, err := kv.TryAcquire(context.Background(), "mev-boost/leader", "instance-a", 15*time.Second)
+	if err != nil {
+		t.Fatalf("TryAcquire returned error: %v", err)
+	}
+	if ok {
+		t.Fatal("expected TryAcquire to fail when the key is already held (nil bulk reply)")
+	}
+}
+
+func TestRedisKVGetReturnsBulkString(t *testing.T) {
+	addr := fakeRedisServer(t, bulkReply("instance-a"))
+	kv := &redisKV{addr: addr}
+
+	value, ok, err := kv.Get(context.Background(), "mev-boost/leader")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if !ok || value != "instance-a" {
+		t.Fatalf("expected value %q ok=true, got %q ok=%v", "instance-a", value, ok)
+	}
+}
+
+func TestRedisKVDoReturnsErrorOnErrorReply(t *testing.T) {
+	addr := fakeRedisServer(t, "-ERR something went wrong\r\n")
+	kv := &redisKV{addr: addr}
+
+	_, _, err := kv.do(context.Background(), "GET", "mev-boost/leader")
+	if err == nil {
+		t.Fatal("expected an error for a RESP error reply")
+	}
+}
+
+func TestNewRedisKVRequiresHost(t *testing.T) {
+	u, _ := url.Parse("redis://")
+	if _, err := newRedisKV(u); err == nil {
+		t.Fatal("expected newRedisKV to reject a url with no host")
+	}
+}
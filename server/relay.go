@@ -0,0 +1,358 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// getHeaderResponse is the subset of the builder-API getHeader response this
+// proxy needs in order to pick the best bid; the raw body is forwarded to the
+// proposer untouched.
+type getHeaderResponse struct {
+	Data struct {
+		Message struct {
+			Value string `json:"value"`
+		} `json:"message"`
+	} `json:"data"`
+}
+
+// relayClient builds an *http.Client for an outgoing relay call, honoring the
+// egress allowlist (if configured) via its DialContext.
+func (m *BoostService) relayClient(timeout time.Duration) *http.Client {
+	transport := &http.Transport{}
+	if m.allowlist != nil {
+		transport.DialContext = m.dialContext(&net.Dialer{Timeout: timeout})
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// callRelay issues method/path against relay, gated by its circuit breaker
+// and rate limiter, and returns the response body on a 2xx status. The result
+// (success or failure) feeds back into the relay's circuit breaker.
+func (m *BoostService) callRelay(ctx context.Context, relay RelayEntry, method, path string, body []byte, timeout time.Duration) ([]byte, error) {
+	relayName := relay.String()
+	if err := m.allowRelayCall(relayName); err != nil {
+		return nil, err
+	}
+
+	relayURL := *relay.URL
+	relayURL.Path = path
+	var reqBody io.Reader
+	if body != nil {
+		reqBody = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, relayURL.String(), reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if requestID := relayRequestID(ctx); requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+
+	resp, err := m.relayClient(timeout).Do(req)
+	if err != nil {
+		m.recordRelayCallResult(relayName, false)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		m.recordRelayCallResult(relayName, false)
+		return nil, err
+	}
+	m.logRelayResponseBody(ctx, relayName, respBody)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		m.recordRelayCallResult(relayName, false)
+		return nil, fmt.Errorf("relay %s returned status %d", relayName, resp.StatusCode)
+	}
+	m.recordRelayCallResult(relayName, true)
+	return respBody, nil
+}
+
+// handleGetHeader fans out GET /eth/v1/builder/header/{slot}/{parentHash}/{pubkey}
+// to every configured relay and returns the highest-value bid at or above
+// -min-bid, with each relay call gated by its circuit breaker, rate limiter
+// and the egress allowlist.
+func (m *BoostService) handleGetHeader(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := m.loggerFromContext(ctx)
+
+	suffix := strings.TrimPrefix(r.URL.Path, "/eth/v1/builder/header/")
+	if len(strings.Split(suffix, "/")) != 3 {
+		http.Error(w, "expected /eth/v1/builder/header/{slot}/{parentHash}/{pubkey}", http.StatusBadRequest)
+		return
+	}
+	relayPath := "/eth/v1/builder/header/" + suffix
+
+	minBid, ok := new(big.Int).SetString(m.relayMinBid.String(), 10)
+	if !ok {
+		log.Warn("configured min-bid is not a valid integer, ignoring it for this request")
+		minBid = nil
+	}
+
+	type bid struct {
+		body   []byte
+		value  *big.Int
+		weight int
+		relay  string
+	}
+
+	relays := *m.relays.Load()
+	bidCh := make(chan bid, len(relays))
+	var wg sync.WaitGroup
+	for _, relay := range relays {
+		wg.Add(1)
+		go func(relay RelayEntry) {
+			defer wg.Done()
+			body, err := m.callRelay(ctx, relay, http.MethodGet, relayPath, nil, m.requestTimeoutGetHeader)
+			if err != nil {
+				log.WithError(err).WithField("relay", relay.String()).Warn("getHeader failed")
+				return
+			}
+			var parsed getHeaderResponse
+			if err := json.Unmarshal(body, &parsed); err != nil {
+				log.WithError(err).WithField("relay", relay.String()).Warn("getHeader returned an unparsable bid")
+				return
+			}
+			value, ok := new(big.Int).SetString(parsed.Data.Message.Value, 10)
+			if !ok {
+				log.WithField("relay", relay.String()).Warn("getHeader bid value is not a valid integer")
+				return
+			}
+			if cap := m.relayBidCapWei(relay); cap != nil && value.Cmp(cap) > 0 {
+				value = cap
+			}
+			bidCh <- bid{body: body, value: value, weight: m.relayWeight(relay), relay: relay.String()}
+		}(relay)
+	}
+	go func() {
+		wg.Wait()
+		close(bidCh)
+	}()
+
+	var best *bid
+	for b := range bidCh {
+		b := b
+		if minBid != nil && b.value.Cmp(minBid) < 0 {
+			continue
+		}
+		switch {
+		case best == nil:
+			best = &b
+		case b.value.Cmp(best.value) > 0:
+			best = &b
+		case b.value.Cmp(best.value) == 0 && b.weight > best.weight:
+			best = &b
+		}
+	}
+
+	if best == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	log.WithFields(logrus.Fields{"relay": best.relay, "value": best.value.String()}).Info("getHeader: selected best bid")
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(best.body)
+}
+
+// blindedBlockSlotAndHash pulls just enough of a SignedBlindedBeaconBlock out
+// of the request body to key the cluster's getPayload dedup lock; the body
+// itself is forwarded to the relay untouched regardless of whether this
+// parse succeeds.
+func blindedBlockSlotAndHash(body []byte) (slot, blockHash string) {
+	var parsed struct {
+		Message struct {
+			Slot string `json:"slot"`
+			Body struct {
+				ExecutionPayloadHeader struct {
+					BlockHash string `json:"block_hash"`
+				} `json:"execution_payload_header"`
+			} `json:"body"`
+		} `json:"message"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return "", ""
+	}
+	return parsed.Message.Slot, parsed.Message.Body.ExecutionPayloadHeader.BlockHash
+}
+
+// handleGetPayload forwards the signed blinded block to the relays and
+// returns the first full payload received. When clustering is enabled,
+// concurrent instances sharing a validator set dedup on (slot, block_hash)
+// via the cluster KV so only one of them calls the relays; the rest wait for
+// the cached result instead of all hitting every relay for the same payload.
+func (m *BoostService) handleGetPayload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := m.loggerFromContext(ctx)
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed reading request body", http.StatusBadRequest)
+		return
+	}
+
+	slot, blockHash := blindedBlockSlotAndHash(body)
+	dedup := m.cluster != nil && slot != "" && blockHash != ""
+
+	if dedup {
+		if cached, ok := m.cluster.CachedPayload(ctx, slot, blockHash); ok {
+			log.Info("getPayload: serving cached result from cluster KV")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(cached))
+			return
+		}
+		claimed, err := m.cluster.ClaimPayload(ctx, slot, blockHash)
+		if err != nil {
+			log.WithError(err).Warn("getPayload: cluster dedup claim failed, falling back to calling relays directly")
+		} else if !claimed {
+			log.Info("getPayload: another instance already claimed this payload, polling cache")
+			cached, ok := m.pollCachedPayload(ctx, slot, blockHash)
+			if ok {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(cached))
+				return
+			}
+			log.Warn("getPayload: cached payload never appeared, falling back to calling relays directly")
+		}
+	}
+
+	payload, relay, err := m.fanOutGetPayload(ctx, body)
+	if err != nil {
+		log.WithError(err).Error("getPayload failed on every relay")
+		http.Error(w, "no relay returned a payload", http.StatusBadGateway)
+		return
+	}
+	log.WithField("relay", relay).Info("getPayload succeeded")
+
+	if dedup {
+		if err := m.cluster.CachePayload(ctx, slot, blockHash, string(payload)); err != nil {
+			log.WithError(err).Warn("getPayload: failed caching payload for cluster dedup")
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(payload)
+}
+
+// pollCachedPayload waits briefly for the instance that claimed this
+// (slot, blockHash) to publish its result, rather than every other instance
+// immediately falling back to calling the relays itself.
+func (m *BoostService) pollCachedPayload(ctx context.Context, slot, blockHash string) (string, bool) {
+	const (
+		interval = 200 * time.Millisecond
+		timeout  = 2 * time.Second
+	)
+	deadline := time.Now().Add(timeout)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return "", false
+		case <-ticker.C:
+			if cached, ok := m.cluster.CachedPayload(ctx, slot, blockHash); ok {
+				return cached, true
+			}
+		}
+	}
+	return "", false
+}
+
+// fanOutGetPayload sends body to every relay concurrently and returns the
+// first successful response.
+func (m *BoostService) fanOutGetPayload(ctx context.Context, body []byte) ([]byte, string, error) {
+	relays := *m.relays.Load()
+	type result struct {
+		body  []byte
+		relay string
+		err   error
+	}
+	resultCh := make(chan result, len(relays))
+	var wg sync.WaitGroup
+	for _, relay := range relays {
+		wg.Add(1)
+		go func(relay RelayEntry) {
+			defer wg.Done()
+			respBody, err := m.callRelay(ctx, relay, http.MethodPost, "/eth/v1/builder/blinded_blocks", body, m.requestTimeoutGetPayload)
+			resultCh <- result{body: respBody, relay: relay.String(), err: err}
+		}(relay)
+	}
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	var lastErr error
+	for res := range resultCh {
+		if res.err != nil {
+			lastErr = res.err
+			continue
+		}
+		return res.body, res.relay, nil
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no relays configured")
+	}
+	return nil, "", lastErr
+}
+
+// handleRegisterValidator fans out POST /eth/v1/builder/validators to every
+// relay. When clustering is enabled, only the current leader forwards
+// registrations to the relays, so instances sharing a validator set behind
+// an HAProxy don't each send every relay the same batch.
+func (m *BoostService) handleRegisterValidator(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := m.loggerFromContext(ctx)
+
+	if m.cluster != nil && !m.cluster.IsLeader() {
+		log.Info("registerValidator: not the cluster leader, skipping relay forward")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "failed reading request body", http.StatusBadRequest)
+		return
+	}
+
+	relays := *m.relays.Load()
+	var okCount atomic.Int32
+	var wg sync.WaitGroup
+	for _, relay := range relays {
+		wg.Add(1)
+		go func(relay RelayEntry) {
+			defer wg.Done()
+			_, err := m.callRelay(ctx, relay, http.MethodPost, "/eth/v1/builder/validators", body, m.requestTimeoutRegVal)
+			if err != nil {
+				log.WithError(err).WithField("relay", relay.String()).Warn("registerValidator failed")
+				return
+			}
+			okCount.Add(1)
+		}(relay)
+	}
+	wg.Wait()
+
+	if okCount.Load() == 0 && len(relays) > 0 {
+		http.Error(w, "no relay accepted the registration", http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
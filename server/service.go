@@ -0,0 +1,340 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/flashbots/go-boost-utils/types"
+	"github.com/sirupsen/logrus"
+)
+
+// listenFDsStart is the first inherited file descriptor number for a process
+// started with ExtraFiles (fd 0-2 are stdin/stdout/stderr), matching the
+// systemd sd_listen_fds convention used when LISTEN_FDS is set.
+const listenFDsStart = 3
+
+// BoostServiceOpts provides all available options for use with NewBoostService.
+type BoostServiceOpts struct {
+	Log                   *logrus.Entry
+	ListenAddr            string
+	Relays                []RelayEntry
+	RelayMonitors         []*url.URL
+	GenesisForkVersionHex string
+	RelayCheck            bool
+	RelayMinBid           types.U256Str
+
+	RequestTimeoutGetHeader  time.Duration
+	RequestTimeoutGetPayload time.Duration
+	RequestTimeoutRegVal     time.Duration
+
+	// ShutdownTimeout bounds how long StartHTTPServer waits for in-flight
+	// requests to complete once its context is canceled.
+	ShutdownTimeout time.Duration
+
+	// OperatorAddr, when set, serves /-/ready and /-/healthy on a separate
+	// listener from ListenAddr so they aren't exposed on the public builder API.
+	OperatorAddr   string
+	UnhealthyAfter time.Duration
+
+	LogRelayResponseBodies bool
+
+	ClusterKV         string
+	ClusterInstanceID string
+
+	RelayRPS            float64
+	EgressAllowlistPath string
+
+	ConfigPath string
+}
+
+// BoostService reverse proxies the beacon-facing builder API to a set of relays.
+type BoostService struct {
+	listenAddr    string
+	relayMonitors []*url.URL
+	log           *logrus.Entry
+	srv           *http.Server
+	operatorSrv   *http.Server
+	listener      net.Listener
+
+	genesisForkVersionHex string
+	relayCheck            bool
+	relayMinBid           types.U256Str
+
+	requestTimeoutGetHeader  time.Duration
+	requestTimeoutGetPayload time.Duration
+	requestTimeoutRegVal     time.Duration
+	shutdownTimeout          time.Duration
+	unhealthyAfter           time.Duration
+
+	operatorAddr           string
+	logRelayResponseBodies bool
+
+	relayRPS            float64
+	egressAllowlistPath string
+	configPath          string
+	allowlist           *egressAllowlist
+	relayOverrides      map[string]relayOverride
+
+	breakersMu sync.Mutex
+	breakers   map[string]*circuitBreaker
+	limiters   map[string]*rateLimiter
+
+	relays atomic.Pointer[[]RelayEntry]
+
+	listening      atomic.Bool
+	relayCheckDone atomic.Bool
+	shuttingDown   atomic.Bool
+	lastRelayErr   atomic.Value // string
+	lastRelayOK    atomic.Value // time.Time
+
+	cluster *clusterElector
+}
+
+// NewBoostService created a new BoostService.
+func NewBoostService(opts BoostServiceOpts) (*BoostService, error) {
+	if opts.ListenAddr == "" {
+		return nil, errors.New("listen address is required")
+	}
+	// With -config set, the relay list may live entirely in the config file;
+	// NewBoostService validates that a non-empty relay set results once
+	// ReloadConfig has run, below.
+	if len(opts.Relays) == 0 && opts.ConfigPath == "" {
+		return nil, errors.New("at least one relay is required")
+	}
+
+	m := &BoostService{
+		listenAddr:               opts.ListenAddr,
+		relayMonitors:            opts.RelayMonitors,
+		log:                      opts.Log,
+		genesisForkVersionHex:    opts.GenesisForkVersionHex,
+		relayCheck:               opts.RelayCheck,
+		relayMinBid:              opts.RelayMinBid,
+		requestTimeoutGetHeader:  opts.RequestTimeoutGetHeader,
+		requestTimeoutGetPayload: opts.RequestTimeoutGetPayload,
+		requestTimeoutRegVal:     opts.RequestTimeoutRegVal,
+		shutdownTimeout:          opts.ShutdownTimeout,
+		unhealthyAfter:           opts.UnhealthyAfter,
+		operatorAddr:             opts.OperatorAddr,
+		logRelayResponseBodies:   opts.LogRelayResponseBodies,
+		relayRPS:                 opts.RelayRPS,
+		egressAllowlistPath:      opts.EgressAllowlistPath,
+		configPath:               opts.ConfigPath,
+		breakers:                 make(map[string]*circuitBreaker),
+		limiters:                 make(map[string]*rateLimiter),
+	}
+
+	relays := append([]RelayEntry(nil), opts.Relays...)
+	m.relays.Store(&relays)
+	m.lastRelayErr.Store("")
+	m.lastRelayOK.Store(time.Now())
+	// /-/ready waits on the initial relay check only if one was requested.
+	m.relayCheckDone.Store(!opts.RelayCheck)
+
+	mux := http.NewServeMux()
+	m.registerRoutes(mux)
+	if opts.OperatorAddr == "" {
+		// No separate operator listener configured: keep the health endpoints
+		// on the public builder API, as before -operator-addr existed.
+		m.registerHealthRoutes(mux)
+	}
+	m.srv = &http.Server{Addr: opts.ListenAddr, Handler: mux}
+
+	if opts.OperatorAddr != "" {
+		operatorMux := http.NewServeMux()
+		m.registerHealthRoutes(operatorMux)
+		m.operatorSrv = &http.Server{Addr: opts.OperatorAddr, Handler: operatorMux}
+	}
+
+	if opts.ClusterKV != "" {
+		elector, err := newClusterElector(opts.ClusterKV, opts.ClusterInstanceID, m.log)
+		if err != nil {
+			return nil, fmt.Errorf("failed initializing cluster kv backend: %w", err)
+		}
+		m.cluster = elector
+	}
+
+	if opts.EgressAllowlistPath != "" {
+		allowlist, err := loadEgressAllowlist(opts.EgressAllowlistPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed loading egress allowlist: %w", err)
+		}
+		m.allowlist = allowlist
+	}
+
+	if opts.ConfigPath != "" {
+		if err := m.ReloadConfig(opts.ConfigPath); err != nil {
+			return nil, fmt.Errorf("failed loading config file: %w", err)
+		}
+	}
+
+	return m, nil
+}
+
+// registerRoutes wires up the beacon-facing builder API routes. Each handler
+// is wrapped with withRequestLogger so it gets a request-correlation id and a
+// context-scoped logger to pass along to any relay calls it makes.
+func (m *BoostService) registerRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("/eth/v1/builder/status", m.withRequestLogger("status", m.handleStatus))
+	mux.HandleFunc("/eth/v1/builder/header/", m.withRequestLogger("getHeader", m.handleGetHeader))
+	mux.HandleFunc("/eth/v1/builder/blinded_blocks", m.withRequestLogger("getPayload", m.handleGetPayload))
+	mux.HandleFunc("/eth/v1/builder/validators", m.withRequestLogger("registerValidator", m.handleRegisterValidator))
+}
+
+func (m *BoostService) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// CheckRelays pings every configured relay's status endpoint and returns how
+// many responded successfully.
+func (m *BoostService) CheckRelays() int {
+	client := &http.Client{Timeout: m.requestTimeoutGetHeader}
+	numOK := 0
+	relays := *m.relays.Load()
+	for _, relay := range relays {
+		statusURL := *relay.URL
+		statusURL.Path = "/eth/v1/builder/status"
+		resp, err := client.Get(statusURL.String())
+		if err != nil {
+			m.log.WithError(err).WithField("relay", relay.String()).Warn("relay status check failed")
+			m.recordRelayError(err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusOK {
+			numOK++
+			m.recordRelaySuccess()
+		}
+	}
+	m.relayCheckDone.Store(true)
+	return numOK
+}
+
+// listen binds listenAddr, adopting a systemd-activated socket via LISTEN_FDS
+// when present instead of calling net.Listen, so SIGHUP can hand the fd to a
+// new binary with zero dropped connections.
+func (m *BoostService) listen() (net.Listener, error) {
+	if fds := os.Getenv("LISTEN_FDS"); fds != "" {
+		n, err := strconv.Atoi(fds)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid LISTEN_FDS value %q", fds)
+		}
+		f := os.NewFile(uintptr(listenFDsStart), "listen_socket")
+		listener, err := net.FileListener(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed adopting inherited listener: %w", err)
+		}
+		m.log.Info("adopted listener from LISTEN_FDS (socket activation)")
+		return listener, nil
+	}
+	return net.Listen("tcp", m.listenAddr)
+}
+
+// StartHTTPServer serves the beacon-facing API (and, if configured, the
+// operator endpoints on their own listener) until ctx is canceled, at which
+// point it drains in-flight requests for up to shutdownTimeout before
+// returning.
+func (m *BoostService) StartHTTPServer(ctx context.Context) error {
+	listener, err := m.listen()
+	if err != nil {
+		return err
+	}
+	m.listener = listener
+	m.listening.Store(true)
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := m.srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+			return
+		}
+		errCh <- nil
+	}()
+
+	if m.operatorSrv != nil {
+		go func() {
+			if err := m.operatorSrv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				m.log.WithError(err).Error("operator endpoint server stopped unexpectedly")
+			}
+		}()
+	}
+
+	go m.pollRelayHealth(ctx)
+
+	select {
+	case <-ctx.Done():
+		m.shuttingDown.Store(true)
+		m.listening.Store(false)
+		if m.cluster != nil {
+			m.cluster.Stop()
+		}
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), m.shutdownTimeout)
+		defer cancel()
+		if m.operatorSrv != nil {
+			_ = m.operatorSrv.Shutdown(shutdownCtx)
+		}
+		if err := m.srv.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("graceful shutdown did not complete within %s: %w", m.shutdownTimeout, err)
+		}
+		return <-errCh
+	case err := <-errCh:
+		return err
+	}
+}
+
+// Restart execs a new copy of the running binary, passing the listening
+// socket via ExtraFiles and LISTEN_FDS=1 in its environment so the new
+// process can adopt it via listen() above. The old process keeps serving
+// in-flight requests; the operator is expected to send SIGTERM to this
+// process once the replacement reports ready, for a zero-dropped-connection
+// handover. Triggered by SIGUSR1 (always), or by SIGHUP when no -config file
+// is in use (see cli.Main's signal handling for the full tradeoff).
+func (m *BoostService) Restart() error {
+	if m.listener == nil {
+		return errors.New("cannot restart before the server has started listening")
+	}
+	tcpListener, ok := m.listener.(*net.TCPListener)
+	if !ok {
+		return fmt.Errorf("restart requires a tcp listener, got %T", m.listener)
+	}
+	listenerFile, err := tcpListener.File()
+	if err != nil {
+		return fmt.Errorf("failed obtaining listener fd: %w", err)
+	}
+	defer listenerFile.Close()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed resolving current executable: %w", err)
+	}
+
+	cmd := exec.Command(executable, os.Args[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = []*os.File{listenerFile}
+	cmd.Env = append(os.Environ(), "LISTEN_FDS=1")
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed starting replacement process: %w", err)
+	}
+	m.log.WithField("pid", cmd.Process.Pid).Info("started replacement process with inherited listener")
+	return nil
+}
+
+func (m *BoostService) recordRelayError(err error) {
+	m.lastRelayErr.Store(err.Error())
+}
+
+func (m *BoostService) recordRelaySuccess() {
+	m.lastRelayOK.Store(time.Now())
+	m.lastRelayErr.Store("")
+}
@@ -0,0 +1,58 @@
+package server
+
+import (
+	"fmt"
+	"math/big"
+	"net/url"
+
+	"github.com/flashbots/go-boost-utils/types"
+)
+
+// RelayEntry represents a single relay that mev-boost can connect to, parsed from
+// a URL of the form scheme://pubkey@host.
+type RelayEntry struct {
+	PublicKey string
+	URL       *url.URL
+}
+
+// NewRelayEntry creates a new RelayEntry from a relay URL string.
+func NewRelayEntry(relayURL string) (entry RelayEntry, err error) {
+	u, err := url.Parse(relayURL)
+	if err != nil {
+		return entry, err
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return entry, fmt.Errorf("missing relay public key in %q", relayURL)
+	}
+	entry.PublicKey = u.User.Username()
+	entry.URL = u
+	return entry, nil
+}
+
+// String implements fmt.Stringer.
+func (r RelayEntry) String() string {
+	if r.URL == nil {
+		return ""
+	}
+	return r.URL.String()
+}
+
+// FloatEthToWei converts an ETH-denominated amount (as accepted by -min-bid
+// and the config file's min_bid) into wei, shared by cli.Main and
+// ReloadConfig so both apply the same conversion to the same field.
+func FloatEthToWei(val float64) (types.U256Str, error) {
+	bigval := new(big.Float)
+	bigval.SetFloat64(val)
+
+	wad := new(big.Float)
+	wad.SetInt(big.NewInt(1000000000000000000))
+
+	bigval.Mul(bigval, wad)
+
+	result := new(big.Int)
+	bigval.Int(result)
+
+	var u256 types.U256Str
+	err := u256.FromBig(result)
+	return u256, err
+}